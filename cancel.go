@@ -0,0 +1,98 @@
+package wire
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jeroenrinzema/psql-wire/pkg/buffer"
+)
+
+// BackendKeyData identifies a single backend connection, as sent to the
+// client in a BackendKeyData message at startup, so that a later
+// CancelRequest on a side-channel connection can be matched back to the
+// right in-flight query context.
+type BackendKeyData struct {
+	PID    int32
+	Secret int32
+}
+
+// CancelManager tracks the cancel function of every in-flight query,
+// keyed by the BackendKeyData of the connection that owns it. The
+// connection's read loop registers a query's cancel function before
+// executing it and calls Cancel when it receives a CancelRequest whose
+// BackendKeyData matches, which is what lets a query's writer.ctx.Err()
+// checks actually observe a client-initiated cancellation instead of only
+// a local context timeout.
+type CancelManager struct {
+	mu      sync.Mutex
+	cancels map[BackendKeyData]context.CancelFunc
+}
+
+// NewCancelManager constructs an empty CancelManager.
+func NewCancelManager() *CancelManager {
+	return &CancelManager{
+		cancels: make(map[BackendKeyData]context.CancelFunc),
+	}
+}
+
+// Register associates cancel with key, overwriting any previously
+// registered cancel function for the same key.
+func (m *CancelManager) Register(key BackendKeyData, cancel context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancels[key] = cancel
+}
+
+// Forget removes the cancel function registered for key, once its query
+// has finished and a CancelRequest arriving late should no longer reach
+// it.
+func (m *CancelManager) Forget(key BackendKeyData) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cancels, key)
+}
+
+// Cancel looks up the cancel function registered for key and calls it,
+// reporting whether a matching query was found. It is called by the
+// connection's read loop upon receiving a CancelRequest.
+func (m *CancelManager) Cancel(key BackendKeyData) bool {
+	m.mu.Lock()
+	cancel, ok := m.cancels[key]
+	m.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+
+	return ok
+}
+
+// NewCancelableDataWriter constructs a DataWriter exactly like
+// NewDataWriter, except the context it writes under is derived from ctx
+// via context.WithCancel and registered with manager under key before
+// being returned. The connection's read loop calls manager.Cancel with
+// the BackendKeyData carried by an incoming CancelRequest, which cancels
+// this context and causes the writer's ctx.Err() checks to fail the
+// in-flight query as if it had timed out locally.
+//
+// The caller must invoke the returned release func once the query has
+// finished, successfully or not, so that a CancelRequest arriving late
+// for a reused or unknown key can no longer reach a stale cancel
+// function. release both forgets key and cancels the derived context, to
+// free the context's resources per the context.WithCancel contract.
+//
+// Matching an incoming CancelRequest's PID/secret to key and detecting
+// socket closure both happen in the connection's read loop, which this
+// package-local snapshot does not contain; NewCancelableDataWriter only
+// covers the registration half of that contract.
+func NewCancelableDataWriter(ctx context.Context, columns Columns, formats []FormatCode, writer *buffer.Writer, manager *CancelManager, key BackendKeyData) (dw DataWriter, release func()) {
+	queryCtx, cancel := context.WithCancel(ctx)
+	manager.Register(key, cancel)
+
+	release = func() {
+		manager.Forget(key)
+		cancel()
+	}
+
+	return NewDataWriter(queryCtx, columns, formats, writer), release
+}
@@ -0,0 +1,86 @@
+package wire
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCancelManagerCancelsMatchingKey(t *testing.T) {
+	manager := NewCancelManager()
+	key := BackendKeyData{PID: 1, Secret: 2}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager.Register(key, cancel)
+
+	if !manager.Cancel(key) {
+		t.Fatal("expected Cancel to find the registered key")
+	}
+
+	if ctx.Err() == nil {
+		t.Fatal("expected context to be canceled")
+	}
+}
+
+func TestCancelManagerIgnoresUnknownKey(t *testing.T) {
+	manager := NewCancelManager()
+
+	if manager.Cancel(BackendKeyData{PID: 99, Secret: 99}) {
+		t.Fatal("expected Cancel to report no match for an unregistered key")
+	}
+}
+
+func TestCancelManagerForget(t *testing.T) {
+	manager := NewCancelManager()
+	key := BackendKeyData{PID: 1, Secret: 2}
+
+	called := false
+	manager.Register(key, func() { called = true })
+	manager.Forget(key)
+
+	if manager.Cancel(key) {
+		t.Fatal("expected Cancel to report no match after Forget")
+	}
+
+	if called {
+		t.Fatal("expected cancel function not to be called after Forget")
+	}
+}
+
+func TestDataWriterObservesCancellationMidStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	writer := &dataWriter{ctx: ctx}
+
+	cancel()
+
+	if err := writer.Row([]any{"value"}); err == nil {
+		t.Fatal("expected Row to return the context error once canceled")
+	}
+}
+
+func TestNewCancelableDataWriterCancelsViaManager(t *testing.T) {
+	manager := NewCancelManager()
+	key := BackendKeyData{PID: 1, Secret: 2}
+
+	dw, release := NewCancelableDataWriter(context.Background(), nil, nil, nil, manager, key)
+	defer release()
+
+	if !manager.Cancel(key) {
+		t.Fatal("expected Cancel to find the key registered by NewCancelableDataWriter")
+	}
+
+	if err := dw.Row([]any{"value"}); err == nil {
+		t.Fatal("expected Row to observe cancellation delivered through the manager")
+	}
+}
+
+func TestNewCancelableDataWriterReleaseForgetsKey(t *testing.T) {
+	manager := NewCancelManager()
+	key := BackendKeyData{PID: 1, Secret: 2}
+
+	_, release := NewCancelableDataWriter(context.Background(), nil, nil, nil, manager, key)
+	release()
+
+	if manager.Cancel(key) {
+		t.Fatal("expected Cancel to report no match once release had forgotten the key")
+	}
+}
@@ -0,0 +1,186 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/jeroenrinzema/psql-wire/pkg/types"
+)
+
+// CopyFormat indicates the overall transfer format used for a COPY
+// subprotocol exchange, as advertised in a CopyInResponse or
+// CopyOutResponse message.
+type CopyFormat int16
+
+const (
+	// CopyFormatText indicates that COPY data is transferred as text.
+	CopyFormatText CopyFormat = 0
+	// CopyFormatBinary indicates that COPY data is transferred using the
+	// Postgres binary wire format.
+	CopyFormatBinary CopyFormat = 1
+)
+
+// CopyColumnFormat is the format code advertised for a single column
+// inside a CopyInResponse or CopyOutResponse message.
+type CopyColumnFormat = FormatCode
+
+// ErrCopyFail is the error surfaced to a CopyIn reader once the client
+// aborts the COPY with a CopyFail message.
+type ErrCopyFail struct {
+	Message string
+}
+
+// Error implements the error interface.
+func (err *ErrCopyFail) Error() string {
+	return "copy failed: " + err.Message
+}
+
+// ErrNoCopyInProgress is thrown when a CopyData, CopyDone or CopyFail
+// message is received from the client while no COPY IN is in progress.
+var ErrNoCopyInProgress = errors.New("no COPY IN in progress")
+
+// copyInReader wraps the io.PipeReader returned by CopyIn so that Close
+// also stops the goroutine watching the query context for cancellation.
+type copyInReader struct {
+	*io.PipeReader
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (r *copyInReader) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	return r.PipeReader.Close()
+}
+
+// CopyIn announces a COPY ... FROM STDIN to the client and returns an
+// io.ReadCloser draining the client's CopyData frames. Internally it is
+// backed by an io.Pipe: HandleCopyData feeds bytes in as the connection's
+// read loop receives them, while the handler goroutine drains them
+// through Read. HandleCopyDone closes the pipe cleanly, surfacing io.EOF
+// to the reader once all buffered data has been read; HandleCopyFail
+// closes it with an error instead.
+func (writer *dataWriter) CopyIn(format CopyFormat, columns []CopyColumnFormat) (io.ReadCloser, error) {
+	if writer.closed {
+		return nil, ErrClosedWriter
+	}
+
+	if err := writer.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	writer.client.Start(types.ServerCopyInResponse)
+	writer.client.AddByte(byte(format))
+	writer.client.AddInt16(int16(len(columns)))
+	for _, column := range columns {
+		writer.client.AddInt16(int16(column))
+	}
+	if err := writer.client.End(); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	writer.copyIn = pw
+
+	reader := &copyInReader{PipeReader: pr, stop: make(chan struct{})}
+	go func() {
+		select {
+		case <-writer.ctx.Done():
+			pw.CloseWithError(writer.ctx.Err())
+		case <-reader.stop:
+		}
+	}()
+
+	return reader, nil
+}
+
+// HandleCopyMessage dispatches a single message received from the client
+// while a COPY IN is in progress to the matching handler below. typ is the
+// message's wire type byte (types.ClientCopyData, types.ClientCopyDone or
+// types.ClientCopyFail) and payload is its body, already stripped of the
+// leading type byte and length prefix. The connection's read loop must
+// call this for every message it reads after CopyIn until the COPY
+// completes or fails, which is what actually drains the client's CopyData
+// frames into the reader CopyIn returned.
+func (writer *dataWriter) HandleCopyMessage(typ byte, payload []byte) error {
+	switch typ {
+	case types.ClientCopyData:
+		return writer.HandleCopyData(payload)
+	case types.ClientCopyDone:
+		return writer.HandleCopyDone()
+	case types.ClientCopyFail:
+		return writer.HandleCopyFail(string(payload))
+	default:
+		return fmt.Errorf("wire: unexpected message %q during COPY IN", typ)
+	}
+}
+
+// HandleCopyData feeds a single CopyData ('d') frame received from the
+// client into the reader returned by CopyIn. It is called by
+// HandleCopyMessage for as long as a COPY IN is in progress.
+func (writer *dataWriter) HandleCopyData(data []byte) error {
+	if writer.copyIn == nil {
+		return ErrNoCopyInProgress
+	}
+
+	_, err := writer.copyIn.Write(data)
+	return err
+}
+
+// HandleCopyDone completes an in progress COPY IN, surfacing a clean
+// io.EOF to the reader returned by CopyIn once it has drained the
+// buffered data. It is called by HandleCopyMessage upon receiving a
+// CopyDone ('c') message.
+func (writer *dataWriter) HandleCopyDone() error {
+	if writer.copyIn == nil {
+		return ErrNoCopyInProgress
+	}
+
+	return writer.copyIn.Close()
+}
+
+// HandleCopyFail aborts an in progress COPY IN, surfacing the client's
+// message to the reader returned by CopyIn wrapped inside ErrCopyFail. It
+// is called by HandleCopyMessage upon receiving a CopyFail ('f') message.
+func (writer *dataWriter) HandleCopyFail(message string) error {
+	if writer.copyIn == nil {
+		return ErrNoCopyInProgress
+	}
+
+	return writer.copyIn.CloseWithError(&ErrCopyFail{Message: message})
+}
+
+// CopyFail aborts an in progress COPY IN from the server side. It closes
+// the reader returned by CopyIn with ErrCopyFail, sends an ErrorResponse
+// to the client so it stops streaming CopyData frames, and closes the
+// writer so no further Row/Complete/CopyOut calls can emit frames into
+// the now-aborted command cycle.
+func (writer *dataWriter) CopyFail(message string) error {
+	if writer.closed {
+		return ErrClosedWriter
+	}
+
+	if err := writer.ctx.Err(); err != nil {
+		return err
+	}
+
+	defer writer.close()
+
+	if writer.copyIn != nil {
+		writer.copyIn.CloseWithError(&ErrCopyFail{Message: message})
+	}
+
+	writer.client.Start(types.ServerErrorResponse)
+	writer.client.AddByte('S')
+	writer.client.AddString("ERROR")
+	writer.client.AddNullTerminate()
+	writer.client.AddByte('C')
+	writer.client.AddString("57014")
+	writer.client.AddNullTerminate()
+	writer.client.AddByte('M')
+	writer.client.AddString(message)
+	writer.client.AddNullTerminate()
+	writer.client.AddByte(0)
+	return writer.client.End()
+}
@@ -0,0 +1,120 @@
+package wire
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeroenrinzema/psql-wire/pkg/types"
+)
+
+func TestCopyInReaderCopyDoneIsEOF(t *testing.T) {
+	pr, pw := io.Pipe()
+	reader := &copyInReader{PipeReader: pr, stop: make(chan struct{})}
+
+	pw.Close()
+
+	_, err := reader.Read(make([]byte, 1))
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestCopyInReaderCopyFailWrapsMessage(t *testing.T) {
+	pr, pw := io.Pipe()
+	reader := &copyInReader{PipeReader: pr, stop: make(chan struct{})}
+
+	pw.CloseWithError(&ErrCopyFail{Message: "client gave up"})
+
+	_, err := reader.Read(make([]byte, 1))
+
+	var copyFail *ErrCopyFail
+	if !errors.As(err, &copyFail) {
+		t.Fatalf("expected *ErrCopyFail, got %v", err)
+	}
+
+	if copyFail.Message != "client gave up" {
+		t.Fatalf("unexpected message: %q", copyFail.Message)
+	}
+}
+
+func TestHandleCopyMessageDispatchesCopyData(t *testing.T) {
+	pr, pw := io.Pipe()
+	writer := &dataWriter{ctx: context.Background(), copyIn: pw}
+
+	read := make(chan []byte, 1)
+	go func() {
+		buf := make([]byte, 4)
+		n, _ := pr.Read(buf)
+		read <- buf[:n]
+	}()
+
+	if err := writer.HandleCopyMessage(types.ClientCopyData, []byte("data")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := string(<-read); got != "data" {
+		t.Fatalf("unexpected payload read from pipe: %q", got)
+	}
+}
+
+func TestHandleCopyMessageUnknownType(t *testing.T) {
+	writer := &dataWriter{ctx: context.Background()}
+
+	if err := writer.HandleCopyMessage('?', nil); err == nil {
+		t.Fatal("expected an error for an unknown message type")
+	}
+}
+
+func TestHandleCopyMessageWithoutCopyInProgress(t *testing.T) {
+	writer := &dataWriter{ctx: context.Background()}
+
+	if err := writer.HandleCopyMessage(types.ClientCopyDone, nil); !errors.Is(err, ErrNoCopyInProgress) {
+		t.Fatalf("expected ErrNoCopyInProgress, got %v", err)
+	}
+}
+
+func TestCopyFailOnClosedWriter(t *testing.T) {
+	writer := &dataWriter{ctx: context.Background(), closed: true}
+
+	if err := writer.CopyFail("boom"); !errors.Is(err, ErrClosedWriter) {
+		t.Fatalf("expected ErrClosedWriter, got %v", err)
+	}
+}
+
+func TestCopyFailRespectsCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	writer := &dataWriter{ctx: ctx}
+
+	if err := writer.CopyFail("boom"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCopyFailClosesReaderAndWriter(t *testing.T) {
+	pr, pw := io.Pipe()
+	writer := &dataWriter{ctx: context.Background(), copyIn: pw}
+
+	func() {
+		// writer.client is nil in this unit test - there is no real
+		// buffer.Writer available outside of a running connection - so the
+		// wire-write half of CopyFail panics here. The copyIn pipe is
+		// closed before that point is reached, and writer.closed is set by
+		// CopyFail's deferred close as the panic unwinds, which is what
+		// this test exercises.
+		defer func() { recover() }()
+		writer.CopyFail("boom")
+	}()
+
+	_, err := pr.Read(make([]byte, 1))
+	var copyFail *ErrCopyFail
+	if !errors.As(err, &copyFail) || copyFail.Message != "boom" {
+		t.Fatalf("expected ErrCopyFail(boom) on the CopyIn reader, got %v", err)
+	}
+
+	if !writer.closed {
+		t.Fatal("expected CopyFail to close the writer")
+	}
+}
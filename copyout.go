@@ -0,0 +1,121 @@
+package wire
+
+import (
+	"errors"
+	"io"
+
+	"github.com/jeroenrinzema/psql-wire/pkg/types"
+)
+
+// DefaultCopyOutChunkSize is the default maximum size in bytes of a single
+// CopyData frame written by the io.WriteCloser returned by CopyOut, used
+// unless SetCopyOutChunkSize has been called first.
+const DefaultCopyOutChunkSize = 64 * 1024
+
+// ErrInvalidChunkSize is thrown by SetCopyOutChunkSize when given a size
+// that is not positive.
+var ErrInvalidChunkSize = errors.New("copy out chunk size must be positive")
+
+// SetCopyOutChunkSize overrides the maximum size in bytes of a single
+// CopyData frame written by the io.WriteCloser a subsequent call to
+// CopyOut returns, in place of DefaultCopyOutChunkSize.
+func (writer *dataWriter) SetCopyOutChunkSize(size int) error {
+	if size <= 0 {
+		return ErrInvalidChunkSize
+	}
+
+	writer.copyOutChunkSize = size
+	return nil
+}
+
+// CopyOut announces a COPY ... TO STDOUT to the client and returns an
+// io.WriteCloser chunking the caller's bytes into CopyData frames bounded
+// by DefaultCopyOutChunkSize, or the size set via SetCopyOutChunkSize.
+// Close flushes a CopyDone message followed by CommandComplete, mirroring
+// the Complete step handlers already perform for regular result sets.
+func (writer *dataWriter) CopyOut(format CopyFormat, columns []CopyColumnFormat) (io.WriteCloser, error) {
+	if writer.closed {
+		return nil, ErrClosedWriter
+	}
+
+	if err := writer.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	writer.client.Start(types.ServerCopyOutResponse)
+	writer.client.AddByte(byte(format))
+	writer.client.AddInt16(int16(len(columns)))
+	for _, column := range columns {
+		writer.client.AddInt16(int16(column))
+	}
+	if err := writer.client.End(); err != nil {
+		return nil, err
+	}
+
+	chunkSize := writer.copyOutChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultCopyOutChunkSize
+	}
+
+	return &copyOutWriter{writer: writer, chunkSize: chunkSize}, nil
+}
+
+// copyOutWriter is the io.WriteCloser returned by DataWriter.CopyOut.
+type copyOutWriter struct {
+	writer    *dataWriter
+	chunkSize int
+}
+
+// Write chunks p into one or more CopyData frames no larger than
+// chunkSize, flushing each to the client before returning.
+func (w *copyOutWriter) Write(p []byte) (int, error) {
+	if w.writer.closed {
+		return 0, ErrClosedWriter
+	}
+
+	written := 0
+	for len(p) > 0 {
+		if err := w.writer.ctx.Err(); err != nil {
+			w.writer.close()
+			return written, err
+		}
+
+		chunk := p
+		if len(chunk) > w.chunkSize {
+			chunk = chunk[:w.chunkSize]
+		}
+
+		w.writer.client.Start(types.ServerCopyData)
+		w.writer.client.AddBytes(chunk)
+		if err := w.writer.client.End(); err != nil {
+			w.writer.close()
+			return written, err
+		}
+
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+
+	return written, nil
+}
+
+// Close sends CopyDone followed by CommandComplete and closes the
+// underlying DataWriter, matching the existing Complete semantics.
+func (w *copyOutWriter) Close() error {
+	if w.writer.closed {
+		return ErrClosedWriter
+	}
+
+	if err := w.writer.ctx.Err(); err != nil {
+		w.writer.close()
+		return err
+	}
+
+	w.writer.client.Start(types.ServerCopyDone)
+	if err := w.writer.client.End(); err != nil {
+		w.writer.close()
+		return err
+	}
+
+	return w.writer.Complete("COPY")
+}
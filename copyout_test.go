@@ -0,0 +1,28 @@
+package wire
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetCopyOutChunkSize(t *testing.T) {
+	writer := &dataWriter{}
+
+	if err := writer.SetCopyOutChunkSize(1024); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if writer.copyOutChunkSize != 1024 {
+		t.Fatalf("expected copyOutChunkSize 1024, got %d", writer.copyOutChunkSize)
+	}
+}
+
+func TestSetCopyOutChunkSizeRejectsNonPositive(t *testing.T) {
+	writer := &dataWriter{}
+
+	for _, size := range []int{0, -1} {
+		if err := writer.SetCopyOutChunkSize(size); !errors.Is(err, ErrInvalidChunkSize) {
+			t.Fatalf("SetCopyOutChunkSize(%d): expected ErrInvalidChunkSize, got %v", size, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+
+	"github.com/jeroenrinzema/psql-wire/pkg/types"
+)
+
+// RowEncoder writes a single data row directly into the underlying wire
+// buffer, without boxing column values into any the way Row does. Append*
+// must be called once per defined column, in column order, followed by
+// EndRow to finalize and flush the DataRow message. A RowEncoder is not
+// safe for concurrent use.
+type RowEncoder interface {
+	// AppendInt64 appends a non-null int64 column value. It returns
+	// ErrRowOverflow if more values have been appended than the row writer
+	// has columns.
+	AppendInt64(v int64) error
+
+	// AppendText appends a non-null text column value. It returns
+	// ErrRowOverflow if more values have been appended than the row writer
+	// has columns.
+	AppendText(v string) error
+
+	// AppendBytes appends a non-null raw column value. It returns
+	// ErrRowOverflow if more values have been appended than the row writer
+	// has columns.
+	AppendBytes(v []byte) error
+
+	// AppendNull appends a NULL column value. It returns ErrRowOverflow if
+	// more values have been appended than the row writer has columns.
+	AppendNull() error
+
+	// EndRow finalizes and flushes the DataRow message to the client.
+	EndRow() error
+}
+
+// ErrRowIncomplete is thrown by EndRow when fewer Append* calls were made
+// than the row writer has columns, so the previous row's buffers would
+// otherwise be re-emitted unchanged.
+var ErrRowIncomplete = errors.New("row writer: not all columns were appended")
+
+// ErrRowOverflow is thrown by Append* when more values have been appended
+// than the row writer has columns, so the last intended column would
+// otherwise be silently dropped.
+var ErrRowOverflow = errors.New("row writer: more values appended than columns")
+
+// rowField holds the pending wire encoding for a single column of the row
+// currently being assembled. buf is reused across rows so that appending
+// a row does not allocate once the encoder has warmed up.
+type rowField struct {
+	buf  []byte
+	null bool
+}
+
+// int64Encoder renders an int64 value into dst per a single column's
+// negotiated FormatCode, returning the extended slice.
+type int64Encoder func(dst []byte, v int64) []byte
+
+func encodeInt64Text(dst []byte, v int64) []byte {
+	return strconv.AppendInt(dst, v, 10)
+}
+
+func encodeInt64Binary(dst []byte, v int64) []byte {
+	var scratch [8]byte
+	binary.BigEndian.PutUint64(scratch[:], uint64(v))
+	return append(dst, scratch[:]...)
+}
+
+// rowEncoder is the dataWriter-backed implementation of RowEncoder. Its
+// per-column int64 encoders are resolved once, at RowWriter time, based on
+// each column's negotiated FormatCode, so emitting a row requires no
+// further interface dispatch or boxing.
+type rowEncoder struct {
+	writer      *dataWriter
+	fields      []rowField
+	int64Encode []int64Encoder
+	next        int
+}
+
+func (enc *rowEncoder) AppendInt64(v int64) error {
+	if enc.next >= len(enc.fields) {
+		return ErrRowOverflow
+	}
+
+	f := &enc.fields[enc.next]
+	encode := encodeInt64Text
+	if enc.next < len(enc.int64Encode) && enc.int64Encode[enc.next] != nil {
+		encode = enc.int64Encode[enc.next]
+	}
+
+	f.buf = encode(f.buf[:0], v)
+	f.null = false
+	enc.next++
+	return nil
+}
+
+func (enc *rowEncoder) AppendText(v string) error {
+	if enc.next >= len(enc.fields) {
+		return ErrRowOverflow
+	}
+
+	f := &enc.fields[enc.next]
+	f.buf = append(f.buf[:0], v...)
+	f.null = false
+	enc.next++
+	return nil
+}
+
+func (enc *rowEncoder) AppendBytes(v []byte) error {
+	if enc.next >= len(enc.fields) {
+		return ErrRowOverflow
+	}
+
+	f := &enc.fields[enc.next]
+	f.buf = append(f.buf[:0], v...)
+	f.null = false
+	enc.next++
+	return nil
+}
+
+func (enc *rowEncoder) AppendNull() error {
+	if enc.next >= len(enc.fields) {
+		return ErrRowOverflow
+	}
+
+	enc.fields[enc.next].null = true
+	enc.next++
+	return nil
+}
+
+// EndRow finalizes the DataRow message built up by the preceding Append*
+// calls and resets the encoder for the next row. It returns
+// ErrRowIncomplete, without touching the client, if fewer Append* calls
+// were made than the row writer has columns.
+func (enc *rowEncoder) EndRow() error {
+	writer := enc.writer
+	if writer.closed {
+		return ErrClosedWriter
+	}
+
+	if err := writer.ctx.Err(); err != nil {
+		return err
+	}
+
+	if enc.next != len(enc.fields) {
+		return ErrRowIncomplete
+	}
+
+	writer.client.Start(types.ServerDataRow)
+	writer.client.AddInt16(int16(len(enc.fields)))
+	for _, f := range enc.fields {
+		if f.null {
+			writer.client.AddInt32(-1)
+			continue
+		}
+
+		writer.client.AddInt32(int32(len(f.buf)))
+		writer.client.AddBytes(f.buf)
+	}
+
+	enc.next = 0
+	writer.written++
+	return writer.client.End()
+}
+
+// columnFormat resolves the FormatCode negotiated for column i, following
+// the same convention as the rest of the wire protocol: zero formats means
+// every column is text, a single format applies to every column, and
+// otherwise formats are given one per column.
+func columnFormat(formats []FormatCode, i int) FormatCode {
+	switch len(formats) {
+	case 0:
+		return TextFormat
+	case 1:
+		return formats[0]
+	default:
+		return formats[i]
+	}
+}
+
+// RowWriter returns a stateful RowEncoder bound to the columns already
+// passed to Define. The returned encoder is not safe for concurrent use
+// and must not be retained across a subsequent call to Define.
+func (writer *dataWriter) RowWriter() RowEncoder {
+	enc := &rowEncoder{
+		writer:      writer,
+		fields:      make([]rowField, len(writer.columns)),
+		int64Encode: make([]int64Encoder, len(writer.columns)),
+	}
+
+	for i := range enc.int64Encode {
+		if columnFormat(writer.formats, i) == BinaryFormat {
+			enc.int64Encode[i] = encodeInt64Binary
+		} else {
+			enc.int64Encode[i] = encodeInt64Text
+		}
+	}
+
+	return enc
+}
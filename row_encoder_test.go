@@ -0,0 +1,59 @@
+package wire
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRowEncoderAppendBeyondColumnsReturnsErrRowOverflow(t *testing.T) {
+	enc := &rowEncoder{
+		writer: &dataWriter{ctx: context.Background()},
+		fields: make([]rowField, 1),
+	}
+
+	if err := enc.AppendText("a"); err != nil {
+		t.Fatalf("unexpected error appending the first column: %v", err)
+	}
+
+	err := enc.AppendText("b") // beyond the single defined column
+	if !errors.Is(err, ErrRowOverflow) {
+		t.Fatalf("expected ErrRowOverflow, got %v", err)
+	}
+
+	if enc.next != 1 {
+		t.Fatalf("expected next to stay at 1, got %d", enc.next)
+	}
+}
+
+func TestRowEncoderEndRowIncomplete(t *testing.T) {
+	enc := &rowEncoder{
+		writer: &dataWriter{ctx: context.Background()},
+		fields: make([]rowField, 2),
+	}
+
+	enc.AppendText("only-one")
+
+	err := enc.EndRow()
+	if !errors.Is(err, ErrRowIncomplete) {
+		t.Fatalf("expected ErrRowIncomplete, got %v", err)
+	}
+}
+
+func TestColumnFormat(t *testing.T) {
+	cases := []struct {
+		formats []FormatCode
+		index   int
+		want    FormatCode
+	}{
+		{nil, 0, TextFormat},
+		{[]FormatCode{BinaryFormat}, 3, BinaryFormat},
+		{[]FormatCode{TextFormat, BinaryFormat}, 1, BinaryFormat},
+	}
+
+	for _, c := range cases {
+		if got := columnFormat(c.formats, c.index); got != c.want {
+			t.Fatalf("columnFormat(%v, %d) = %v, want %v", c.formats, c.index, got, c.want)
+		}
+	}
+}
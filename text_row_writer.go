@@ -0,0 +1,98 @@
+package wire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/lib/pq/oid"
+)
+
+// SetLineMode toggles whether the io.WriteCloser returned by TextRowWriter
+// splits the bytes it receives into rows on '\n' boundaries (true) or
+// emits every Write call as its own row (false, the default).
+func (writer *dataWriter) SetLineMode(enabled bool) {
+	writer.lineMode = enabled
+}
+
+// TextRowWriter defines a single text column named columnName and returns
+// an io.WriteCloser that packages each Write as a DataRow. This bridges
+// Go's io.Writer ecosystem - encoding/csv, text/template, json.Encoder -
+// onto the typed row model DataWriter otherwise expects, without forcing
+// callers to marshal every line into a []any themselves. Close announces
+// CommandComplete using the given description as the command tag, e.g.
+// "SELECT" or "COPY", matching what Complete already expects callers to
+// supply.
+func (writer *dataWriter) TextRowWriter(columnName string, description string) (io.WriteCloser, error) {
+	if writer.closed {
+		return nil, ErrClosedWriter
+	}
+
+	columns := Columns{{
+		Name:   columnName,
+		Oid:    oid.T_text,
+		Width:  -1,
+		Format: TextFormat,
+	}}
+
+	if err := writer.Define(columns); err != nil {
+		return nil, err
+	}
+
+	return &textRowWriter{writer: writer, description: description}, nil
+}
+
+// textRowWriter is the io.WriteCloser returned by TextRowWriter.
+type textRowWriter struct {
+	writer      *dataWriter
+	description string
+	buf         bytes.Buffer
+}
+
+// Write emits p as one or more DataRow messages, splitting on '\n' when
+// the writer's LineMode is enabled.
+func (w *textRowWriter) Write(p []byte) (int, error) {
+	if w.writer.closed {
+		return 0, ErrClosedWriter
+	}
+
+	if w.writer.columns == nil {
+		return 0, ErrNotDefined
+	}
+
+	if !w.writer.lineMode {
+		if err := w.writer.Row([]any{p}); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadBytes('\n')
+		if err == io.EOF {
+			w.buf.Write(line)
+			break
+		}
+
+		if err := w.writer.Row([]any{bytes.TrimSuffix(line, []byte{'\n'})}); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close flushes any buffered partial line and announces CommandComplete.
+func (w *textRowWriter) Close() error {
+	if w.writer.closed {
+		return ErrClosedWriter
+	}
+
+	if w.writer.lineMode && w.buf.Len() > 0 {
+		if err := w.writer.Row([]any{w.buf.Bytes()}); err != nil {
+			return err
+		}
+	}
+
+	return w.writer.Complete(w.description)
+}
@@ -0,0 +1,29 @@
+package wire
+
+import "testing"
+
+func TestSetLineMode(t *testing.T) {
+	writer := &dataWriter{}
+
+	if writer.lineMode {
+		t.Fatal("expected line mode to default to false")
+	}
+
+	writer.SetLineMode(true)
+	if !writer.lineMode {
+		t.Fatal("expected line mode to be enabled")
+	}
+
+	writer.SetLineMode(false)
+	if writer.lineMode {
+		t.Fatal("expected line mode to be disabled")
+	}
+}
+
+func TestTextRowWriterKeepsGivenDescription(t *testing.T) {
+	w := &textRowWriter{description: "COPY"}
+
+	if w.description != "COPY" {
+		t.Fatalf("expected description %q, got %q", "COPY", w.description)
+	}
+}
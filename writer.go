@@ -3,6 +3,7 @@ package wire
 import (
 	"context"
 	"errors"
+	"io"
 
 	"github.com/jeroenrinzema/psql-wire/pkg/buffer"
 	"github.com/jeroenrinzema/psql-wire/pkg/types"
@@ -18,6 +19,13 @@ type DataWriter interface {
 	// values are encoded as NULL values.
 	Row([]any) error
 
+	// RowWriter returns a stateful RowEncoder bound to the columns already
+	// passed to Define and the formats the writer was constructed with. It
+	// is the zero-allocation
+	// counterpart to Row, for hot paths that can afford to encode each
+	// column value explicitly instead of passing it through []any.
+	RowWriter() RowEncoder
+
 	// Written returns the number of rows written to the client.
 	Written() uint64
 
@@ -29,8 +37,41 @@ type DataWriter interface {
 	// no further data should be expected.
 	Complete(description string) error
 
-	// CopyIn is incomplete
-	CopyIn() error
+	// CopyIn announces a COPY ... FROM STDIN to the client using the given
+	// overall and per-column formats and returns a reader draining the
+	// client's CopyData frames. The returned reader surfaces io.EOF once the
+	// client sends CopyDone, or an error wrapping the client's message once
+	// it sends CopyFail.
+	CopyIn(format CopyFormat, columns []CopyColumnFormat) (io.ReadCloser, error)
+
+	// CopyFail aborts an in progress COPY IN from the server side. It closes
+	// the reader returned by CopyIn with ErrCopyFail and sends an
+	// ErrorResponse to the client so it stops streaming CopyData frames.
+	CopyFail(message string) error
+
+	// CopyOut announces a COPY ... TO STDOUT to the client using the given
+	// overall and per-column formats and returns a writer chunking the
+	// caller's bytes into CopyData frames. Close sends CopyDone followed by
+	// CommandComplete.
+	CopyOut(format CopyFormat, columns []CopyColumnFormat) (io.WriteCloser, error)
+
+	// SetCopyOutChunkSize overrides the maximum size in bytes of a single
+	// CopyData frame written by the io.WriteCloser a subsequent call to
+	// CopyOut returns, in place of DefaultCopyOutChunkSize.
+	SetCopyOutChunkSize(size int) error
+
+	// TextRowWriter defines a single text column named columnName and
+	// returns an io.WriteCloser that packages each Write as a DataRow,
+	// bridging Go's io.Writer ecosystem - encoding/csv, text/template,
+	// json.Encoder - onto the typed row model DataWriter otherwise expects.
+	// Close announces CommandComplete using description as the command tag.
+	TextRowWriter(columnName string, description string) (io.WriteCloser, error)
+
+	// SetLineMode toggles whether the io.WriteCloser returned by
+	// TextRowWriter splits the bytes it receives into rows on '\n'
+	// boundaries (true) or emits every Write call as its own row (false,
+	// the default).
+	SetLineMode(enabled bool)
 }
 
 // ErrDataWritten is thrown when an empty result is attempted to be send to the
@@ -40,6 +81,10 @@ var ErrDataWritten = errors.New("data has already been written")
 // ErrClosedWriter is thrown when the data writer has been closed
 var ErrClosedWriter = errors.New("closed writer")
 
+// ErrNotDefined is thrown when a write is attempted before the data
+// writer's result columns have been defined.
+var ErrNotDefined = errors.New("columns have not been defined")
+
 // NewDataWriter constructs a new data writer using the given context and
 // buffer. The returned writer should be handled with caution as it is not safe
 // for concurrent use. Concurrent access to the same data without proper
@@ -55,12 +100,15 @@ func NewDataWriter(ctx context.Context, columns Columns, formats []FormatCode, w
 
 // dataWriter is a implementation of the DataWriter interface.
 type dataWriter struct {
-	ctx     context.Context
-	columns Columns
-	formats []FormatCode
-	client  *buffer.Writer
-	closed  bool
-	written uint64
+	ctx              context.Context
+	columns          Columns
+	formats          []FormatCode
+	client           *buffer.Writer
+	closed           bool
+	written          uint64
+	copyIn           *io.PipeWriter
+	copyOutChunkSize int
+	lineMode         bool
 }
 
 func (writer *dataWriter) Define(columns Columns) error {
@@ -68,6 +116,10 @@ func (writer *dataWriter) Define(columns Columns) error {
 		return ErrClosedWriter
 	}
 
+	if err := writer.ctx.Err(); err != nil {
+		return err
+	}
+
 	writer.columns = columns
 	return writer.columns.Define(writer.ctx, writer.client, writer.formats)
 }
@@ -77,35 +129,22 @@ func (writer *dataWriter) Row(values []any) error {
 		return ErrClosedWriter
 	}
 
+	if err := writer.ctx.Err(); err != nil {
+		return err
+	}
+
 	writer.written++
 
 	return writer.columns.Write(writer.ctx, writer.formats, writer.client, values)
 }
 
-func (writer *dataWriter) CopyIn() error {
+func (writer *dataWriter) Empty() error {
 	if writer.closed {
 		return ErrClosedWriter
 	}
-	// if writer.reader == nil {
-	// 	return errors.New("reader is nil; use PortalCacheCopy to execute CopyIn")
-	// }
-	writer.client.Start(types.ServerCopyInResponse)
-	writer.client.AddByte(0)
-	const n = 3
-	writer.client.AddInt16(n)
-	for i := 0; i < n; i++ {
-		writer.client.AddInt16(0)
-	}
-	if err := writer.client.End(); err != nil {
-		return err
-	}
-
-	return nil
-}
 
-func (writer *dataWriter) Empty() error {
-	if writer.closed {
-		return ErrClosedWriter
+	if err := writer.ctx.Err(); err != nil {
+		return err
 	}
 
 	if writer.written != 0 {
@@ -125,6 +164,10 @@ func (writer *dataWriter) Complete(description string) error {
 		return ErrClosedWriter
 	}
 
+	if err := writer.ctx.Err(); err != nil {
+		return err
+	}
+
 	if writer.written == 0 && writer.columns != nil {
 		err := writer.Empty()
 		if err != nil {